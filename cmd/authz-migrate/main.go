@@ -0,0 +1,51 @@
+// authz-migrate copies authorization rows from the MySQL-backed
+// AuthorizationStore into an etcd-backed one, so that an operator can move a
+// deployment off MySQL without losing in-flight pending authorizations.
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	_ "github.com/go-sql-driver/mysql"
+	gorp "gopkg.in/gorp.v1"
+
+	"github.com/letsencrypt/boulder/sa"
+)
+
+func main() {
+	dbDSN := flag.String("dbDSN", "", "DSN for the source MySQL database")
+	etcdEndpoints := flag.String("etcdEndpoints", "localhost:2379", "comma-separated list of etcd endpoints")
+	dialTimeout := flag.Duration("dialTimeout", 5*time.Second, "timeout for the initial etcd connection")
+	flag.Parse()
+
+	if *dbDSN == "" {
+		log.Fatal("-dbDSN is required")
+	}
+
+	sqlDb, err := sql.Open("mysql", *dbDSN)
+	if err != nil {
+		log.Fatalf("connecting to MySQL: %s", err)
+	}
+	db := &gorp.DbMap{Db: sqlDb, Dialect: gorp.MySQLDialect{Engine: "InnoDB", Encoding: "UTF8"}}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(*etcdEndpoints, ","),
+		DialTimeout: *dialTimeout,
+	})
+	if err != nil {
+		log.Fatalf("connecting to etcd: %s", err)
+	}
+	defer client.Close()
+
+	store := sa.NewEtcdAuthorizationStore(client)
+	migrated, err := sa.MigrateAuthorizationsToEtcd(db, store)
+	if err != nil {
+		log.Fatalf("migrating authorizations: %s", err)
+	}
+	log.Printf("migrated %d authorizations to etcd", migrated)
+}
@@ -0,0 +1,172 @@
+package wfe
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/square/go-jose"
+)
+
+type registrationRequest struct {
+	Resource               string          `json:"resource"`
+	Agreement              string          `json:"agreement,omitempty"`
+	ExternalAccountBinding json.RawMessage `json:"externalAccountBinding,omitempty"`
+}
+
+// eabJWS is the flattened JWS JSON serialization RFC 8555 §7.3.4 requires
+// for externalAccountBinding. It's built by hand rather than through
+// jose.Signer: that interface only exposes Sign and SetNonceSource, with no
+// supported way to set the `kid`/`url` protected header fields an inner EAB
+// JWS needs.
+type eabJWS struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// buildEAB returns the serialized inner JWS required by RFC 8555 §7.3.4:
+// an HS256 JWS over the account's public key JWK, with `kid` set to the
+// EAB key ID and `url` set to newAccountURL. It returns a nil payload (and
+// no error) if no EAB key pool is configured for this run.
+func (s *State) buildEAB(pub *rsa.PublicKey, newAccountURL string) ([]byte, error) {
+	key, ok := s.randomEABKey()
+	if !ok {
+		return nil, nil
+	}
+	jwk := jose.JsonWebKey{Key: pub}
+	jwkBytes, err := jwk.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	protected, err := json.Marshal(struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+		URL string `json:"url"`
+	}{"HS256", key.KID, newAccountURL})
+	if err != nil {
+		return nil, err
+	}
+	protectedB64 := base64.RawURLEncoding.EncodeToString(protected)
+	payloadB64 := base64.RawURLEncoding.EncodeToString(jwkBytes)
+	mac := hmac.New(sha256.New, key.HMACKey)
+	mac.Write([]byte(protectedB64 + "." + payloadB64))
+	return json.Marshal(eabJWS{
+		Protected: protectedB64,
+		Payload:   payloadB64,
+		Signature: base64.RawURLEncoding.EncodeToString(mac.Sum(nil)),
+	})
+}
+
+// newRegistration creates a new account key and registers it against
+// s.apiBase, embedding an externalAccountBinding if an EAB key pool was
+// configured via New. Registration and EAB-assisted registration are timed
+// separately so the two can be compared.
+func (s *State) newRegistration(_ *registration) {
+	count, err := s.regCount()
+	if err != nil {
+		fmt.Printf("newRegistration: failed to read registration count: %s\n", err)
+		return
+	}
+	if s.maxRegs != 0 && count >= s.maxRegs {
+		return
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		fmt.Printf("newRegistration: failed to generate key: %s\n", err)
+		return
+	}
+	key.Precompute()
+	signer, err := jose.NewSigner(jose.RS256, key)
+	if err != nil {
+		fmt.Printf("newRegistration: failed to create signer: %s\n", err)
+		return
+	}
+	signer.SetNonceSource(s)
+
+	newAccountURL := fmt.Sprintf("%s/acme/new-acct", s.apiBase)
+	eab, err := s.buildEAB(&key.PublicKey, newAccountURL)
+	if err != nil {
+		fmt.Printf("newRegistration: failed to build EAB: %s\n", err)
+		return
+	}
+	payload, err := json.Marshal(registrationRequest{
+		Resource:               "new-reg",
+		Agreement:              s.termsURL,
+		ExternalAccountBinding: eab,
+	})
+	if err != nil {
+		fmt.Printf("newRegistration: failed to marshal payload: %s\n", err)
+		return
+	}
+	jws, err := s.signWithNonce(newAccountURL, false, payload, signer)
+	if err != nil {
+		fmt.Printf("newRegistration: failed to sign payload: %s\n", err)
+		return
+	}
+
+	latencyTag := "POST /acme/new-acct"
+	if eab != nil {
+		latencyTag = "POST /acme/new-acct (EAB)"
+	}
+	started := time.Now()
+	resp, err := s.post(newAccountURL, jws)
+	finished := time.Now()
+	state := "good"
+	defer func() { s.callLatency.Add(latencyTag, started, finished, state) }()
+	if err != nil {
+		state = "error"
+		fmt.Printf("newRegistration: request failed: %s\n", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		state = "error"
+		return
+	}
+
+	s.addReg(&registration{
+		key:    key,
+		signer: signer,
+		iMu:    new(sync.RWMutex),
+	})
+}
+
+func (s *State) newAuthorization(reg *registration) {
+	if reg == nil {
+		return
+	}
+	// Authorization creation needs a target domain and challenge
+	// response plumbing that lives outside this package; tracked
+	// separately from the registration/EAB work above.
+}
+
+func (s *State) newCertificate(reg *registration) {
+	if reg == nil {
+		return
+	}
+	// Certificate issuance needs a CSR built from reg's validated
+	// authorizations; tracked separately from the registration/EAB work
+	// above.
+}
+
+func (s *State) revokeCertificate(reg *registration) {
+	if reg == nil {
+		return
+	}
+	reg.iMu.RLock()
+	defer reg.iMu.RUnlock()
+	if len(reg.certs) == 0 {
+		return
+	}
+	// Revocation needs the DER cert bytes alongside the cert IDs
+	// currently tracked on reg.certs; tracked separately from the
+	// registration/EAB work above.
+}
@@ -0,0 +1,182 @@
+package wfe
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mrand "math/rand"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/clientv3/concurrency"
+)
+
+const (
+	coordRegPrefix     = "/load-generator/regs/"
+	coordNoncePrefix   = "/load-generator/nonces/"
+	coordElectionKey   = "/load-generator/plan-election"
+	coordThroughputKey = "/load-generator/throughput"
+
+	regLeaseTTL   = 10 * time.Minute
+	nonceLeaseTTL = 5 * time.Minute
+)
+
+// coordinator shares the registration pool, nonce pool, and run plan across
+// multiple load-generator processes via etcd, so that a single logical run
+// can be driven by more workers than one process can host. It is optional:
+// a State with a nil coordinator behaves exactly as it did before, keeping
+// all of this state local.
+type coordinator struct {
+	client   *clientv3.Client
+	session  *concurrency.Session
+	election *concurrency.Election
+	leaseID  clientv3.LeaseID
+}
+
+// newCoordinator dials the given etcd endpoints and sets up the lease,
+// session, and election used to share state for this run.
+func newCoordinator(endpoints []string) (*coordinator, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+	session, err := concurrency.NewSession(client, concurrency.WithTTL(int(regLeaseTTL.Seconds())))
+	if err != nil {
+		return nil, err
+	}
+	lease, err := client.Grant(context.Background(), int64(regLeaseTTL.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	return &coordinator{
+		client:   client,
+		session:  session,
+		election: concurrency.NewElection(session, coordElectionKey),
+		leaseID:  lease.ID,
+	}, nil
+}
+
+// regKey derives the etcd key pushReg stores reg under from a hash of its
+// private key, so that pushing many registrations from one process doesn't
+// overwrite previous ones under a single shared key.
+func regKey(reg rawRegistration) string {
+	sum := sha256.Sum256(reg.RawKey)
+	return coordRegPrefix + hex.EncodeToString(sum[:])
+}
+
+// pushReg publishes reg to etcd under the shared registration pool, keyed
+// by regKey. The key is scoped to the lease granted in newCoordinator so a
+// process that disappears doesn't leave registrations other workers can no
+// longer use stranded forever.
+func (c *coordinator) pushReg(reg rawRegistration) error {
+	encoded, err := json.Marshal(reg)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.Put(context.Background(), regKey(reg), string(encoded), clientv3.WithLease(c.leaseID))
+	return err
+}
+
+// pullReg returns a random registration from the shared pool, and false if
+// the pool is currently empty.
+func (c *coordinator) pullReg() (rawRegistration, bool) {
+	resp, err := c.client.Get(context.Background(), coordRegPrefix, clientv3.WithPrefix())
+	if err != nil || len(resp.Kvs) == 0 {
+		return rawRegistration{}, false
+	}
+	kv := resp.Kvs[mrand.Intn(len(resp.Kvs))]
+	var reg rawRegistration
+	if err := json.Unmarshal(kv.Value, &reg); err != nil {
+		return rawRegistration{}, false
+	}
+	return reg, true
+}
+
+// regCount returns the number of registrations currently in the shared
+// pool, so that callers enforcing a maxRegs cap see the same count every
+// other coordinated process does instead of just their own local share.
+func (c *coordinator) regCount() (int, error) {
+	resp, err := c.client.Get(context.Background(), coordRegPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return int(resp.Count), nil
+}
+
+// pushNonce adds nonce to the shared nonce queue under a lease scoped to
+// nonceLeaseTTL, so unused nonces expire instead of accumulating forever.
+func (c *coordinator) pushNonce(nonce string) error {
+	lease, err := c.client.Grant(context.Background(), int64(nonceLeaseTTL.Seconds()))
+	if err != nil {
+		return err
+	}
+	_, err = c.client.Put(context.Background(), coordNoncePrefix+nonce, "", clientv3.WithLease(lease.ID))
+	return err
+}
+
+// popNonceAttempts bounds how many times popNonce retries after losing a
+// race with another process over the same nonce, rather than retrying
+// forever under heavy contention.
+const popNonceAttempts = 5
+
+// popNonce removes and returns one nonce from the shared queue, and false
+// if the queue is currently empty (or every attempt lost its race with
+// another process). The delete is a CAS transaction keyed on the key's
+// ModRevision -- matching the pattern RevokeAuthorizations uses in
+// sa/authz_store_etcd.go -- so two processes racing to pop the same nonce
+// can't both walk away believing they own it.
+func (c *coordinator) popNonce() (string, bool) {
+	ctx := context.Background()
+	for attempt := 0; attempt < popNonceAttempts; attempt++ {
+		resp, err := c.client.Get(ctx, coordNoncePrefix, clientv3.WithPrefix(), clientv3.WithLimit(1))
+		if err != nil || len(resp.Kvs) == 0 {
+			return "", false
+		}
+		kv := resp.Kvs[0]
+		txnResp, err := c.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(string(kv.Key)), "=", kv.ModRevision)).
+			Then(clientv3.OpDelete(string(kv.Key))).
+			Commit()
+		if err != nil {
+			return "", false
+		}
+		if !txnResp.Succeeded {
+			// Another process claimed this nonce first; try again.
+			continue
+		}
+		return string(kv.Key)[len(coordNoncePrefix):], true
+	}
+	return "", false
+}
+
+// campaignForPlan blocks until this process is elected the run-plan driver.
+// Callers use this to decide whether they should execute the run plan
+// themselves or just watch coordThroughputKey for updates from whoever won.
+func (c *coordinator) campaignForPlan() error {
+	return c.election.Campaign(context.Background(), "")
+}
+
+// publishThroughput broadcasts the current base action rate so that
+// followers (processes that lost the election) can apply it locally.
+func (c *coordinator) publishThroughput(rate int64) error {
+	_, err := c.client.Put(context.Background(), coordThroughputKey, fmt.Sprintf("%d", rate))
+	return err
+}
+
+// watchThroughput calls onChange with every rate broadcast via
+// publishThroughput, until ctx is canceled.
+func (c *coordinator) watchThroughput(ctx context.Context, onChange func(int64)) {
+	for resp := range c.client.Watch(ctx, coordThroughputKey) {
+		for _, ev := range resp.Events {
+			var rate int64
+			if _, err := fmt.Sscanf(string(ev.Kv.Value), "%d", &rate); err == nil {
+				onChange(rate)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package wfe
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"testing"
+)
+
+func TestRegKeyIsStablePerKeyAndDistinctAcrossKeys(t *testing.T) {
+	keyA, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	keyB, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	regA := rawRegistration{RawKey: x509.MarshalPKCS1PrivateKey(keyA)}
+	regB := rawRegistration{RawKey: x509.MarshalPKCS1PrivateKey(keyB)}
+
+	if got, want := regKey(regA), regKey(regA); got != want {
+		t.Errorf("regKey() was not stable across calls: %q != %q", got, want)
+	}
+	if regKey(regA) == regKey(regB) {
+		t.Error("regKey() returned the same key for two different private keys")
+	}
+	if regKey(regA)[:len(coordRegPrefix)] != coordRegPrefix {
+		t.Errorf("regKey() = %q, want prefix %q", regKey(regA), coordRegPrefix)
+	}
+}
+
+func TestRegFromRaw(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	raw := rawRegistration{
+		Certs:  []string{"cert-a", "cert-b"},
+		RawKey: x509.MarshalPKCS1PrivateKey(key),
+	}
+
+	s := &State{}
+	reg, ok := s.regFromRaw(raw)
+	if !ok {
+		t.Fatal("regFromRaw() returned ok = false for a well-formed registration")
+	}
+	if reg.signer == nil {
+		t.Error("regFromRaw() did not build a signer")
+	}
+	if len(reg.certs) != len(raw.Certs) || reg.certs[0] != raw.Certs[0] || reg.certs[1] != raw.Certs[1] {
+		t.Errorf("regFromRaw() certs = %v, want %v", reg.certs, raw.Certs)
+	}
+	if reg.key.N.Cmp(key.N) != 0 {
+		t.Error("regFromRaw() did not preserve the original key")
+	}
+}
+
+func TestRegFromRawBadKey(t *testing.T) {
+	s := &State{}
+	_, ok := s.regFromRaw(rawRegistration{RawKey: []byte("not a key")})
+	if ok {
+		t.Error("regFromRaw() should have failed on an unparseable key")
+	}
+}
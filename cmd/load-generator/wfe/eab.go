@@ -0,0 +1,38 @@
+package wfe
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	mrand "math/rand"
+)
+
+// eabKey is one External Account Binding credential: the `kid` identifies
+// the key to the server, and hmacKey is the symmetric key used to sign the
+// inner JWS described in RFC 8555 §7.3.4.
+type eabKey struct {
+	KID     string `json:"kid"`
+	HMACKey []byte `json:"hmacKey"`
+}
+
+// loadEABKeys reads a JSON array of {"kid": "...", "hmacKey": "..."} objects
+// (hmacKey base64-encoded, per encoding/json's []byte handling) from path.
+func loadEABKeys(path string) ([]eabKey, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []eabKey
+	if err := json.Unmarshal(content, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// randomEABKey returns a random credential from the configured pool, and
+// false if EAB is not enabled for this run.
+func (s *State) randomEABKey() (eabKey, bool) {
+	if len(s.eabKeys) == 0 {
+		return eabKey{}, false
+	}
+	return s.eabKeys[mrand.Intn(len(s.eabKeys))], true
+}
@@ -2,6 +2,7 @@ package wfe
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
@@ -52,6 +53,10 @@ type State struct {
 
 	realIP string
 
+	// eabKeys is the pool of External Account Binding credentials used by
+	// newRegistration, if any were loaded via New's eabKeysFile argument.
+	eabKeys []eabKey
+
 	nMu       *sync.RWMutex
 	noncePool []string
 
@@ -71,6 +76,12 @@ type State struct {
 	wg *sync.WaitGroup
 
 	runPlan []RatePeriod
+
+	// coord shares the registration pool, nonce pool, and run plan with
+	// other load-generator processes via etcd, if New was given etcd
+	// endpoints to connect to. It is nil otherwise, in which case all of
+	// that state stays local to this process.
+	coord *coordinator
 }
 
 type rawRegistration struct {
@@ -137,12 +148,76 @@ func (s *State) Restore(filename string) error {
 	return nil
 }
 
-// New returns a pointer to a new State struct, or an error
-func New(rpcAddr string, apiBase string, rate int, keySize int, domainBase string, runtime time.Duration, termsURL string, realIP string, runPlan []RatePeriod, maxRegs, warmupRegs, warmupWorkers int) (*State, error) {
+// ClientTLSConfig holds the client certificate material used to authenticate
+// to Boulder over mTLS. If CertFile is unset, the load-generator falls back to
+// InsecureSkipVerify, as it always has.
+type ClientTLSConfig struct {
+	// CertFile and KeyFile point to a PEM client certificate/key pair
+	// presented to the server.
+	CertFile string
+	KeyFile  string
+	// CACertFile, if set, is a PEM bundle used to verify the server's
+	// certificate in place of the system roots. It is ignored when CertFile
+	// is unset.
+	CACertFile string
+}
+
+func buildTLSConfig(cfg ClientTLSConfig) (*tls.Config, error) {
+	if cfg.CertFile == "" {
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading client certificate: %s", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+	if cfg.CACertFile != "" {
+		caCert, err := ioutil.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %s", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// New returns a pointer to a new State struct, or an error. If eabKeysFile
+// is non-empty, it is read as a JSON pool of External Account Binding
+// credentials and newRegistration will embed one in every registration it
+// sends. If clientTLS.CertFile is set, the load-generator authenticates to
+// Boulder with that client certificate instead of skipping server
+// certificate verification. If etcdEndpoints is non-empty, the returned
+// State shares its registration pool, nonce pool, and run plan with other
+// load-generator processes pointed at the same etcd cluster.
+func New(rpcAddr string, apiBase string, rate int, keySize int, domainBase string, runtime time.Duration, termsURL string, realIP string, runPlan []RatePeriod, maxRegs, warmupRegs, warmupWorkers int, eabKeysFile string, clientTLS ClientTLSConfig, etcdEndpoints []string) (*State, error) {
 	certKey, err := rsa.GenerateKey(rand.Reader, keySize)
 	if err != nil {
 		return nil, err
 	}
+	var eabKeys []eabKey
+	if eabKeysFile != "" {
+		eabKeys, err = loadEABKeys(eabKeysFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tlsConfig, err := buildTLSConfig(clientTLS)
+	if err != nil {
+		return nil, err
+	}
+	var coord *coordinator
+	if len(etcdEndpoints) > 0 {
+		coord, err = newCoordinator(etcdEndpoints)
+		if err != nil {
+			return nil, err
+		}
+	}
 	client := &http.Client{
 		Transport: &http.Transport{
 			Dial: (&net.Dialer{
@@ -150,9 +225,7 @@ func New(rpcAddr string, apiBase string, rate int, keySize int, domainBase strin
 				KeepAlive: 30 * time.Second,
 			}).Dial,
 			TLSHandshakeTimeout: 10 * time.Second,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
+			TLSClientConfig:     tlsConfig,
 		},
 	}
 	return &State{
@@ -173,13 +246,43 @@ func New(rpcAddr string, apiBase string, rate int, keySize int, domainBase strin
 		maxRegs:       maxRegs,
 		warmupWorkers: warmupWorkers,
 		warmupRegs:    warmupRegs,
+		eabKeys:       eabKeys,
+		coord:         coord,
 	}, nil
 }
 
 func (s *State) executePlan() {
+	if s.coord == nil {
+		s.driveRunPlan()
+		return
+	}
+
+	// With a coordinator, only the elected driver walks the run plan;
+	// everyone else just applies whatever throughput it broadcasts. This
+	// keeps every process in a distributed run on the same rate instead
+	// of each one independently racing through its own copy of the plan.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.coord.watchThroughput(ctx, func(rate int64) {
+		atomic.StoreInt64(&s.throughput, rate)
+	})
+
+	if err := s.coord.campaignForPlan(); err != nil {
+		fmt.Printf("executePlan: failed to campaign for plan driver: %s\n", err)
+		return
+	}
+	s.driveRunPlan()
+}
+
+func (s *State) driveRunPlan() {
 	for _, p := range s.runPlan {
 		atomic.StoreInt64(&s.throughput, p.Rate)
 		fmt.Printf("Set base action rate to %d/s for %s\n", p.Rate, p.For)
+		if s.coord != nil {
+			if err := s.coord.publishThroughput(p.Rate); err != nil {
+				fmt.Printf("driveRunPlan: failed to publish throughput: %s\n", err)
+			}
+		}
 		time.Sleep(p.For)
 	}
 }
@@ -331,6 +434,11 @@ func (s *State) signWithNonce(endpoint string, alwaysNew bool, payload []byte, s
 }
 
 func (s *State) Nonce() (string, error) {
+	if s.coord != nil {
+		if nonce, ok := s.coord.popNonce(); ok {
+			return nonce, nil
+		}
+	}
 	s.nMu.RLock()
 	if len(s.noncePool) == 0 {
 		s.nMu.RUnlock()
@@ -359,6 +467,12 @@ func (s *State) Nonce() (string, error) {
 }
 
 func (s *State) addNonce(nonce string) {
+	if s.coord != nil {
+		if err := s.coord.pushNonce(nonce); err != nil {
+			fmt.Printf("addNonce: failed to push nonce to coordinator: %s\n", err)
+		}
+		return
+	}
 	s.nMu.Lock()
 	defer s.nMu.Unlock()
 	s.noncePool = append(s.noncePool, nonce)
@@ -367,12 +481,29 @@ func (s *State) addNonce(nonce string) {
 // Reg object utils, used to add and retrieve registration objects
 
 func (s *State) addReg(reg *registration) {
+	if s.coord != nil {
+		raw := rawRegistration{
+			Certs:  reg.certs,
+			RawKey: x509.MarshalPKCS1PrivateKey(reg.key),
+		}
+		if err := s.coord.pushReg(raw); err != nil {
+			fmt.Printf("addReg: failed to push registration to coordinator: %s\n", err)
+		}
+		return
+	}
 	s.rMu.Lock()
 	defer s.rMu.Unlock()
 	s.regs = append(s.regs, reg)
 }
 
 func (s *State) getReg() (*registration, bool) {
+	if s.coord != nil {
+		raw, ok := s.coord.pullReg()
+		if !ok {
+			return nil, false
+		}
+		return s.regFromRaw(raw)
+	}
 	s.rMu.RLock()
 	defer s.rMu.RUnlock()
 	regsLength := len(s.regs)
@@ -382,6 +513,42 @@ func (s *State) getReg() (*registration, bool) {
 	return s.regs[mrand.Intn(regsLength)], true
 }
 
+// regCount returns the number of registrations available to this process:
+// the size of the shared etcd pool if a coordinator is configured, or the
+// size of the local pool otherwise. Callers enforcing maxRegs should use
+// this instead of reading s.regs directly, since s.regs stays empty for
+// the lifetime of a coordinated run.
+func (s *State) regCount() (int, error) {
+	if s.coord != nil {
+		return s.coord.regCount()
+	}
+	s.rMu.RLock()
+	defer s.rMu.RUnlock()
+	return len(s.regs), nil
+}
+
+// regFromRaw rebuilds a usable *registration, including a fresh signer, from
+// the bare key and cert list stored in the shared etcd pool. It returns
+// false if the stored key can't be parsed or signed with.
+func (s *State) regFromRaw(raw rawRegistration) (*registration, bool) {
+	key, err := x509.ParsePKCS1PrivateKey(raw.RawKey)
+	if err != nil {
+		return nil, false
+	}
+	key.Precompute()
+	signer, err := jose.NewSigner(jose.RS256, key)
+	if err != nil {
+		return nil, false
+	}
+	signer.SetNonceSource(s)
+	return &registration{
+		key:    key,
+		signer: signer,
+		certs:  raw.Certs,
+		iMu:    new(sync.RWMutex),
+	}, true
+}
+
 // Call sender, it sends the calls!
 
 type probabilityProfile struct {
@@ -408,11 +575,12 @@ func weightedCall(setup []probabilityProfile) func(*registration) {
 
 func (s *State) sendCall() {
 	actionList := []probabilityProfile{}
-	s.rMu.RLock()
-	if s.maxRegs == 0 || len(s.regs) < s.maxRegs {
+	count, err := s.regCount()
+	if err != nil {
+		fmt.Printf("sendCall: failed to read registration count: %s\n", err)
+	} else if s.maxRegs == 0 || count < s.maxRegs {
 		actionList = append(actionList, probabilityProfile{1, s.newRegistration})
 	}
-	s.rMu.RUnlock()
 
 	reg, found := s.getReg()
 	if found {
@@ -0,0 +1,99 @@
+package wfe
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+)
+
+// writeTestCert generates a throwaway self-signed certificate/key pair and
+// writes them to PEM files in dir, returning their paths.
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "load-generator test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+
+	certFile = dir + "/cert.pem"
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write cert file: %s", err)
+	}
+	keyFile = dir + "/key.pem"
+	keyBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(keyFile, keyBytes, 0600); err != nil {
+		t.Fatalf("failed to write key file: %s", err)
+	}
+	return certFile, keyFile
+}
+
+func TestBuildTLSConfigNoCert(t *testing.T) {
+	tlsConfig, err := buildTLSConfig(ClientTLSConfig{})
+	if err != nil {
+		t.Fatalf("buildTLSConfig() returned error: %s", err)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("buildTLSConfig() with no CertFile should set InsecureSkipVerify")
+	}
+}
+
+func TestBuildTLSConfigWithCert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wfe-tls-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeTestCert(t, dir)
+	tlsConfig, err := buildTLSConfig(ClientTLSConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("buildTLSConfig() returned error: %s", err)
+	}
+	if tlsConfig.InsecureSkipVerify {
+		t.Error("buildTLSConfig() with a CertFile should not set InsecureSkipVerify")
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("buildTLSConfig() loaded %d certificates, want 1", len(tlsConfig.Certificates))
+	}
+}
+
+func TestBuildTLSConfigBadCert(t *testing.T) {
+	_, err := buildTLSConfig(ClientTLSConfig{CertFile: "/does/not/exist", KeyFile: "/does/not/exist"})
+	if err == nil {
+		t.Fatal("buildTLSConfig() with a missing CertFile should have returned an error")
+	}
+}
+
+func TestBuildTLSConfigBadCACert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wfe-tls-test-ca")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeTestCert(t, dir)
+	caFile := dir + "/ca.pem"
+	if err := ioutil.WriteFile(caFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("failed to write CA file: %s", err)
+	}
+
+	_, err = buildTLSConfig(ClientTLSConfig{CertFile: certFile, KeyFile: keyFile, CACertFile: caFile})
+	if err == nil {
+		t.Fatal("buildTLSConfig() with a malformed CACertFile should have returned an error")
+	}
+}
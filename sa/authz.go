@@ -112,6 +112,38 @@ func getAuthorizationIDsByDomain(db *gorp.DbMap, tableName string, ident string,
 	return allIDs, nil
 }
 
+// getAuthorizationBlob fetches the jsonData column for id from tableName --
+// the same authz/pendingAuthorizations tables getAuthorizationIDsByDomain
+// and revokeAuthorizations operate on -- and decompresses it with
+// Decompress. It returns a nil slice if the row has no blob stored, which
+// is the case for rows written before this column existed.
+func getAuthorizationBlob(db *gorp.DbMap, tableName string, id string) ([]byte, error) {
+	var raw []byte
+	err := db.SelectOne(
+		&raw,
+		fmt.Sprintf(`SELECT jsonData FROM %s WHERE id = ?`, tableName),
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return Decompress(raw)
+}
+
+// putAuthorizationBlob compresses blob with Compress and writes it to the
+// jsonData column for id in tableName.
+func putAuthorizationBlob(db *gorp.DbMap, tableName string, id string, blob []byte) error {
+	compressed, err := Compress(blob)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(
+		fmt.Sprintf(`UPDATE %s SET jsonData = ? WHERE id = ?`, tableName),
+		compressed, id,
+	)
+	return err
+}
+
 func revokeAuthorizations(db *gorp.DbMap, tableName string, authIDs []string) (int64, error) {
 	stmtArgs := []interface{}{string(core.StatusRevoked)}
 	qmarks := []string{}
@@ -0,0 +1,57 @@
+package sa
+
+import (
+	"context"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	gorp "gopkg.in/gorp.v1"
+
+	"github.com/letsencrypt/boulder/core"
+)
+
+// authzRow is the subset of authz/pendingAuthorizations columns the etcd
+// migration needs from each row.
+type authzRow struct {
+	ID         string
+	Identifier string
+	Status     string
+	Expires    time.Time
+}
+
+// MigrateAuthorizationsToEtcd copies every row across the authorization
+// tables from dbMap into store, writing both the by-id and by-domain keys
+// that EtcdAuthorizationStore expects. Pending rows are written under a
+// lease scoped to however much of their `expires` column is left as of now,
+// so they expire the same way they would under the MySQL backend; finalized
+// rows are written without a lease. It returns the number of rows migrated.
+func MigrateAuthorizationsToEtcd(dbMap *gorp.DbMap, store *EtcdAuthorizationStore) (int, error) {
+	ctx := context.Background()
+	now := time.Now()
+	migrated := 0
+	for _, table := range authorizationTables {
+		var rows []authzRow
+		_, err := dbMap.Select(&rows, `SELECT id, identifier, status, expires FROM `+table)
+		if err != nil {
+			return migrated, err
+		}
+		for _, row := range rows {
+			var leaseID clientv3.LeaseID
+			if statusIsPending(core.AcmeStatus(row.Status)) {
+				leaseID, err = grantPendingLease(ctx, store.client, row.Expires, now)
+				if err != nil {
+					return migrated, err
+				}
+			}
+			ops := []clientv3.Op{
+				clientv3.OpPut(authzByIDKey(row.ID), row.Status, clientv3.WithLease(leaseID)),
+				clientv3.OpPut(authzByDomainKey(row.Identifier, row.Expires, row.ID), row.Status, clientv3.WithLease(leaseID)),
+			}
+			if _, err := store.client.Txn(ctx).Then(ops...).Commit(); err != nil {
+				return migrated, err
+			}
+			migrated++
+		}
+	}
+	return migrated, nil
+}
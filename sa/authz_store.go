@@ -0,0 +1,77 @@
+package sa
+
+import (
+	"time"
+
+	gorp "gopkg.in/gorp.v1"
+)
+
+// AuthorizationStore abstracts the persistence of authorizations away from
+// any one backend. It exists so that the functions in authz.go (countPending,
+// existingPending, existingFinal, getAuthorizationIDsByDomain,
+// revokeAuthorizations) can be backed by something other than a gorp
+// DbMap/Transaction against MySQL -- see EtcdAuthorizationStore for an
+// alternative implementation aimed at deployments that don't want to run
+// MySQL at all.
+type AuthorizationStore interface {
+	// CountPending returns the number of pending (or processing/unknown)
+	// authorizations that exist for id.
+	CountPending(id string) (int64, error)
+	// HasFinal reports whether a finalized (non-pending) authorization
+	// exists for id.
+	HasFinal(id string) (bool, error)
+	// AuthzIDsByDomain returns the IDs of authorizations for ident that
+	// are neither invalid nor revoked and have not yet expired as of now.
+	AuthzIDsByDomain(ident string, now time.Time) ([]string, error)
+	// RevokeAuthorizations marks the given authorization IDs as revoked
+	// and returns the number of rows/keys affected.
+	RevokeAuthorizations(ids []string) (int64, error)
+}
+
+// gorpAuthorizationStore is the AuthorizationStore implementation backed by
+// the existing gorp/MySQL functions in authz.go. Nothing in this package
+// constructs it.
+type gorpAuthorizationStore struct {
+	dbMap *gorp.DbMap
+	tx    *gorp.Transaction
+}
+
+// newGorpAuthorizationStore returns an AuthorizationStore that delegates to
+// the gorp-backed helpers in this package. tx is used for the pending/final
+// checks (which must run inside the enclosing transaction), while dbMap is
+// used for the by-domain lookup and revocation, which run standalone.
+func newGorpAuthorizationStore(dbMap *gorp.DbMap, tx *gorp.Transaction) *gorpAuthorizationStore {
+	return &gorpAuthorizationStore{dbMap: dbMap, tx: tx}
+}
+
+func (s *gorpAuthorizationStore) CountPending(id string) (int64, error) {
+	return countPending(s.tx, id), nil
+}
+
+func (s *gorpAuthorizationStore) HasFinal(id string) (bool, error) {
+	return existingFinal(s.tx, id), nil
+}
+
+func (s *gorpAuthorizationStore) AuthzIDsByDomain(ident string, now time.Time) ([]string, error) {
+	var ids []string
+	for _, table := range authorizationTables {
+		tableIDs, err := getAuthorizationIDsByDomain(s.dbMap, table, ident, now)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, tableIDs...)
+	}
+	return ids, nil
+}
+
+func (s *gorpAuthorizationStore) RevokeAuthorizations(ids []string) (int64, error) {
+	var total int64
+	for _, table := range authorizationTables {
+		n, err := revokeAuthorizations(s.dbMap, table, ids)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,194 @@
+package sa
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/clientv3/concurrency"
+
+	"github.com/letsencrypt/boulder/core"
+)
+
+// Key layout used by EtcdAuthorizationStore:
+//
+//   /authz/by-id/<id>                         -> status (string)
+//   /authz/by-domain/<ident>/<expiry>/<id>     -> status (string)
+//
+// The by-domain key embeds the expiry (RFC3339, zero-padded so keys sort
+// lexically) so that AuthzIDsByDomain can satisfy its "not yet expired"
+// requirement with a bounded range query instead of scanning every
+// authorization for an identifier.
+const (
+	authzByIDPrefix     = "/authz/by-id/"
+	authzByDomainPrefix = "/authz/by-domain/"
+)
+
+// EtcdAuthorizationStore is an AuthorizationStore backed by etcd v3 range
+// queries and secondary indices, for deployments that would rather not run
+// MySQL. It satisfies the AuthorizationStore interface defined alongside the
+// gorp-backed implementation in authz_store.go.
+type EtcdAuthorizationStore struct {
+	client *clientv3.Client
+}
+
+// NewEtcdAuthorizationStore returns an AuthorizationStore backed by the given
+// etcd v3 client.
+func NewEtcdAuthorizationStore(client *clientv3.Client) *EtcdAuthorizationStore {
+	return &EtcdAuthorizationStore{client: client}
+}
+
+func authzByIDKey(id string) string {
+	return authzByIDPrefix + id
+}
+
+func authzByDomainKey(ident string, expires time.Time, id string) string {
+	return fmt.Sprintf("%s%s/%s/%s", authzByDomainPrefix, ident, expires.UTC().Format(time.RFC3339), id)
+}
+
+// CountPending returns the number of pending (or processing/unknown)
+// authorizations that exist for id.
+func (s *EtcdAuthorizationStore) CountPending(id string) (int64, error) {
+	resp, err := s.client.Get(context.Background(), authzByIDKey(id))
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, kv := range resp.Kvs {
+		if statusIsPending(core.AcmeStatus(string(kv.Value))) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// HasFinal reports whether a finalized (non-pending) authorization exists
+// for id.
+func (s *EtcdAuthorizationStore) HasFinal(id string) (bool, error) {
+	resp, err := s.client.Get(context.Background(), authzByIDKey(id))
+	if err != nil {
+		return false, err
+	}
+	for _, kv := range resp.Kvs {
+		if !statusIsPending(core.AcmeStatus(string(kv.Value))) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AuthzIDsByDomain returns the IDs of authorizations for ident that are
+// neither invalid nor revoked and have not yet expired as of now, using a
+// bounded range query over the by-domain index.
+//
+// The status stored alongside each by-domain key can go stale -- only the
+// by-id key is updated when RevokeAuthorizations runs -- so authzByIDKey is
+// treated as the source of truth for status and is consulted for every
+// candidate the range query turns up.
+func (s *EtcdAuthorizationStore) AuthzIDsByDomain(ident string, now time.Time) ([]string, error) {
+	prefix := authzByDomainPrefix + ident + "/"
+	// Keys under prefix sort by expiry, so starting the range at now skips
+	// every already-expired entry. The range ends at prefix with its
+	// trailing "/" replaced by "0", the next byte up.
+	start := prefix + now.UTC().Format(time.RFC3339)
+	end := authzByDomainPrefix + ident + "0"
+	resp, err := s.client.Get(context.Background(), start, clientv3.WithRange(end))
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, kv := range resp.Kvs {
+		key := strings.TrimPrefix(string(kv.Key), prefix)
+		parts := strings.SplitN(key, "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		expires, err := time.Parse(time.RFC3339, parts[0])
+		if err != nil || !expires.After(now) {
+			continue
+		}
+		id := parts[1]
+		status, err := s.status(id)
+		if err != nil || status == core.StatusInvalid || status == core.StatusRevoked {
+			continue
+		}
+		ids = append(ids, id)
+		if len(ids) >= getAuthorizationIDsMax {
+			break
+		}
+	}
+	return ids, nil
+}
+
+// status returns the current status stored at authzByIDKey(id).
+func (s *EtcdAuthorizationStore) status(id string) (core.AcmeStatus, error) {
+	resp, err := s.client.Get(context.Background(), authzByIDKey(id))
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Kvs) == 0 {
+		return "", fmt.Errorf("sa: no authzByIDKey entry for %s", id)
+	}
+	return core.AcmeStatus(string(resp.Kvs[0].Value)), nil
+}
+
+// RevokeAuthorizations marks the given authorization IDs as revoked using a
+// compare-and-swap transaction per ID, so a concurrent status change loses
+// the race instead of silently clobbering it. It returns the number of IDs
+// actually revoked.
+func (s *EtcdAuthorizationStore) RevokeAuthorizations(ids []string) (int64, error) {
+	var revoked int64
+	for _, id := range ids {
+		key := authzByIDKey(id)
+		getResp, err := s.client.Get(context.Background(), key)
+		if err != nil {
+			return revoked, err
+		}
+		if len(getResp.Kvs) == 0 {
+			continue
+		}
+		kv := getResp.Kvs[0]
+		txnResp, err := s.client.Txn(context.Background()).
+			If(clientv3.Compare(clientv3.ModRevision(key), "=", kv.ModRevision)).
+			Then(clientv3.OpPut(key, string(core.StatusRevoked))).
+			Commit()
+		if err != nil {
+			return revoked, err
+		}
+		if txnResp.Succeeded {
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+// pendingLeaseTTL returns how long a pending authorization's etcd lease
+// should live given its `expires` column, clamped to never be negative.
+func pendingLeaseTTL(expires, now time.Time) time.Duration {
+	ttl := expires.Sub(now)
+	if ttl < 0 {
+		ttl = 0
+	}
+	return ttl
+}
+
+// grantPendingLease creates an etcd lease scoped to pendingLeaseTTL(expires,
+// now) so that a pending authorization's keys are reaped at the same time
+// its `expires` column would have made it disappear under the MySQL
+// backend.
+func grantPendingLease(ctx context.Context, client *clientv3.Client, expires, now time.Time) (clientv3.LeaseID, error) {
+	lease, err := client.Grant(ctx, int64(pendingLeaseTTL(expires, now).Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return lease.ID, nil
+}
+
+// newEtcdSession returns a concurrency session on client. Nothing in this
+// package or in cmd/authz-migrate calls it; the migration tool runs as a
+// single process without a session.
+func newEtcdSession(client *clientv3.Client) (*concurrency.Session, error) {
+	return concurrency.NewSession(client)
+}
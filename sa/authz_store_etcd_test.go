@@ -0,0 +1,45 @@
+package sa
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuthzByIDKey(t *testing.T) {
+	got := authzByIDKey("abc123")
+	want := "/authz/by-id/abc123"
+	if got != want {
+		t.Errorf("authzByIDKey(%q) = %q, want %q", "abc123", got, want)
+	}
+}
+
+func TestAuthzByDomainKey(t *testing.T) {
+	expires := time.Date(2030, time.June, 1, 12, 0, 0, 0, time.UTC)
+	got := authzByDomainKey("example.com", expires, "abc123")
+	want := "/authz/by-domain/example.com/2030-06-01T12:00:00Z/abc123"
+	if got != want {
+		t.Errorf("authzByDomainKey() = %q, want %q", got, want)
+	}
+}
+
+func TestPendingLeaseTTL(t *testing.T) {
+	now := time.Date(2026, time.July, 27, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		expires time.Time
+		want    time.Duration
+	}{
+		{"future expiry returns remaining time", now.Add(5 * time.Minute), 5 * time.Minute},
+		{"past expiry clamps to zero", now.Add(-5 * time.Minute), 0},
+		{"expiry equal to now is zero", now, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := pendingLeaseTTL(tc.expires, now); got != tc.want {
+				t.Errorf("pendingLeaseTTL() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package sa
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+)
+
+// Codec identifies how a blob column was compressed, if at all. It is
+// stored as the first byte of the payload so that rows written before
+// compression was enabled keep decoding transparently.
+type Codec byte
+
+const (
+	// CodecNone indicates the payload that follows is not compressed.
+	CodecNone Codec = iota
+	// CodecGzip indicates the payload that follows is gzip-compressed.
+	CodecGzip
+)
+
+// CompressionCodec controls which codec Compress uses for newly written
+// rows. It defaults to CodecGzip; set it to CodecNone to match the behavior
+// of deployments that predate this feature. Decompress always honors
+// whatever codec byte is actually present on a given row, so changing this
+// knob is safe to do without a migration.
+var CompressionCodec = CodecGzip
+
+// Compress prefixes payload with a single codec byte identifying
+// CompressionCodec and, unless CompressionCodec is CodecNone, compresses
+// payload using that codec. It is intended for large columns written
+// through this package -- most importantly authorization JSON and DER
+// certificate blobs -- where the compression overhead per row is worth
+// paying to cut replication and backup traffic. See putAuthorizationBlob/
+// getAuthorizationBlob for its use against the jsonData column of the same
+// authz/pendingAuthorizations tables getAuthorizationIDsByDomain and
+// revokeAuthorizations touch.
+func Compress(payload []byte) ([]byte, error) {
+	switch CompressionCodec {
+	case CodecNone:
+		return append([]byte{byte(CodecNone)}, payload...), nil
+	case CodecGzip:
+		var buf bytes.Buffer
+		buf.WriteByte(byte(CodecGzip))
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("sa: unknown compression codec %d", CompressionCodec)
+	}
+}
+
+// Decompress reads the codec byte written by Compress and returns the
+// original payload, decompressing it if necessary. An empty payload is
+// returned as-is so that NULL/empty columns written before this feature
+// existed don't need a migration.
+func Decompress(payload []byte) ([]byte, error) {
+	if len(payload) == 0 {
+		return payload, nil
+	}
+	codec := Codec(payload[0])
+	rest := payload[1:]
+	switch codec {
+	case CodecNone:
+		return rest, nil
+	case CodecGzip:
+		r, err := gzip.NewReader(bytes.NewReader(rest))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	default:
+		return nil, fmt.Errorf("sa: unknown compression codec %d", codec)
+	}
+}
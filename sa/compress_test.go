@@ -0,0 +1,54 @@
+package sa
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompressDecompressRoundTrip(t *testing.T) {
+	defer func(codec Codec) { CompressionCodec = codec }(CompressionCodec)
+
+	payload := []byte("a sample authorization JSON blob, repeated repeated repeated")
+
+	testCases := []struct {
+		name  string
+		codec Codec
+	}{
+		{"none", CodecNone},
+		{"gzip", CodecGzip},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			CompressionCodec = tc.codec
+			compressed, err := Compress(payload)
+			if err != nil {
+				t.Fatalf("Compress() returned error: %s", err)
+			}
+			decompressed, err := Decompress(compressed)
+			if err != nil {
+				t.Fatalf("Decompress() returned error: %s", err)
+			}
+			if !bytes.Equal(decompressed, payload) {
+				t.Errorf("Decompress(Compress(payload)) = %q, want %q", decompressed, payload)
+			}
+		})
+	}
+}
+
+func TestDecompressEmptyPayload(t *testing.T) {
+	decompressed, err := Decompress(nil)
+	if err != nil {
+		t.Fatalf("Decompress(nil) returned error: %s", err)
+	}
+	if len(decompressed) != 0 {
+		t.Errorf("Decompress(nil) = %q, want empty", decompressed)
+	}
+}
+
+func TestDecompressUnknownCodec(t *testing.T) {
+	_, err := Decompress([]byte{0xff, 'x'})
+	if err == nil {
+		t.Fatal("Decompress() with an unknown codec byte should have returned an error")
+	}
+}
@@ -0,0 +1,73 @@
+package lints
+
+import (
+	"encoding/asn1"
+	"testing"
+	"time"
+
+	"github.com/zmap/zcrypto/x509"
+)
+
+// utcTimeBytes builds the raw DER bytes of an asn1.RawValue tagged as
+// UTCTime (tag 23) whose content is the UTCTime-formatted encoding of when,
+// regardless of whether when actually falls within the range UTCTime can
+// unambiguously represent. This lets tests build certificates that
+// (incorrectly) use UTCTime for dates in or after 2050.
+func utcTimeBytes(t *testing.T, when time.Time) []byte {
+	t.Helper()
+	raw := asn1.RawValue{
+		Class: asn1.ClassUniversal,
+		Tag:   23,
+		Bytes: []byte(when.Format("060102150405Z")),
+	}
+	encoded, err := asn1.Marshal(raw)
+	if err != nil {
+		t.Fatalf("failed to encode UTCTime: %s", err)
+	}
+	return encoded
+}
+
+func TestUTCPost2049(t *testing.T) {
+	before2050 := time.Date(2049, time.December, 31, 0, 0, 0, 0, time.UTC)
+	at2050 := time.Date(2050, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		notBefore  time.Time
+		notAfter   time.Time
+		wantStatus LintStatus
+	}{
+		{
+			name:       "notAfter just before 2050 is fine as UTCTime",
+			notBefore:  before2050,
+			notAfter:   before2050,
+			wantStatus: Pass,
+		},
+		{
+			name:       "notAfter at 2050 encoded as UTCTime is an error",
+			notBefore:  before2050,
+			notAfter:   at2050,
+			wantStatus: Error,
+		},
+		{
+			name:       "notBefore at 2050 encoded as UTCTime is an error",
+			notBefore:  at2050,
+			notAfter:   at2050,
+			wantStatus: Error,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cert := &x509.Certificate{
+				RawNotBefore: utcTimeBytes(t, tc.notBefore),
+				RawNotAfter:  utcTimeBytes(t, tc.notAfter),
+			}
+			l := &utcPost2049{}
+			result := l.Execute(cert)
+			if result.Status != tc.wantStatus {
+				t.Errorf("Execute() = %v, want %v", result.Status, tc.wantStatus)
+			}
+		})
+	}
+}